@@ -18,20 +18,33 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/user"
 	"path"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"sigs.k8s.io/yaml"
+
+	"github.com/thetechnick/go-space-prompt/internal/config"
+	"github.com/thetechnick/go-space-prompt/internal/gitprovider"
+	"github.com/thetechnick/go-space-prompt/internal/plugin"
+	"github.com/thetechnick/go-space-prompt/internal/plugin/proto"
 )
 
 type Context struct {
@@ -41,6 +54,20 @@ type Context struct {
 	Home         string
 }
 
+// DefaultModuleTimeout is the deadline applied to every module when
+// SPACE_PROMPT_TIMEOUT is unset. A hanging `git status` on an NFS mount or a
+// slow `go version` must never freeze the shell.
+const DefaultModuleTimeout = 200 * time.Millisecond
+
+func moduleTimeout() time.Duration {
+	if v := os.Getenv("SPACE_PROMPT_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultModuleTimeout
+}
+
 func main() {
 	// Init
 	ctx := &Context{
@@ -69,41 +96,116 @@ func main() {
 		color = "blue"
 	}
 
-	// Run Modules
-	var (
-		user       = &UserModule{}
-		kubernetes = &KubernetesModule{}
-		directory  = &DirectoryModule{}
-		git        = &GitModule{}
-		golang     = &GolangModule{}
-		hostname   = &HostnameModule{}
-		status     = &StatusModule{}
-		took       = &TookModule{}
-	)
-	modules := []module{
-		user, kubernetes, directory,
-		git, golang, hostname, status,
-		took,
+	cfg, err := config.Load(home)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-	var wg sync.WaitGroup
-	wg.Add(len(modules))
-	for _, m := range modules {
-		go func(m module) {
-			must(m.Init(ctx))
-			wg.Done()
-		}(m)
+
+	// Run Modules
+	namedModules := map[string]module{
+		"user":       &UserModule{},
+		"kubernetes": &KubernetesModule{},
+		"container":  &ContainerModule{},
+		"directory":  &DirectoryModule{},
+		"git":        &GitModule{},
+		"golang":     &GolangModule{},
+		"hostname":   &HostnameModule{},
+		"status":     &StatusModule{},
+		"took":       &TookModule{},
+	}
+	deadline := moduleTimeout()
+	runCtx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	debug := os.Getenv("SPACE_PROMPT_DEBUG") != ""
+
+	type job struct {
+		name string
+		m    module
+	}
+	type result struct {
+		name, output string
+	}
+	jobs := make(chan job, len(namedModules))
+	for name, m := range namedModules {
+		jobs <- job{name: name, m: m}
+	}
+	close(jobs)
+
+	results := make(chan result, len(namedModules))
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go func() {
+			for j := range jobs {
+				// Each module additionally gets its own deadline of the same
+				// length, derived from the global one, so it never runs past
+				// the global deadline. A module queued behind a slow one only
+				// gets what is left of the global deadline.
+				modCtx, modCancel := context.WithTimeout(runCtx, deadline)
+
+				done := make(chan error, 1)
+				go func() { done <- j.m.Init(modCtx, ctx, cfg) }()
+
+				select {
+				case err := <-done:
+					must(err)
+					results <- result{name: j.name, output: j.m.Output()}
+				case <-modCtx.Done():
+					if debug {
+						results <- result{name: j.name, output: "…"}
+					} else {
+						results <- result{name: j.name}
+					}
+				}
+				modCancel()
+			}
+		}()
+	}
+
+	outputs := map[string]string{}
+	for i := 0; i < len(namedModules); i++ {
+		r := <-results
+		outputs[r.name] = r.output
+	}
+
+	// Run Plugins
+	pluginTimeout := plugin.DefaultTimeout
+	if v := os.Getenv("SPACE_PROMPT_PLUGIN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			pluginTimeout = d
+		}
+	}
+	plugins := plugin.Discover(path.Join(home, ".config", "space-prompt", "plugins"), pluginTimeout)
+	pluginInit := &proto.InitRequest{
+		InSSH:      ctx.InSSH,
+		DurationNs: int64(ctx.Duration),
+		Status:     int32(ctx.Status),
+		Jobs:       int32(ctx.Jobs),
+		Home:       ctx.Home,
+	}
+	pluginsCtx, pluginsCancel := context.WithTimeout(context.Background(), deadline)
+	defer pluginsCancel()
+	var pwg sync.WaitGroup
+	pwg.Add(len(plugins))
+	for _, p := range plugins {
+		go func(p *plugin.Client) {
+			defer pwg.Done()
+			p.Init(pluginsCtx, pluginInit)
+		}(p)
+	}
+	pwg.Wait()
+	for _, p := range plugins {
+		outputs[p.Name] = p.Output()
+		p.Kill()
 	}
-	wg.Wait()
 
 	// Build
 	fmt.Print("\n" +
-		user.Output() + kubernetes.Output() + directory.Output() +
-		git.Output() + golang.Output() + took.Output() + "\n" +
-		hostname.Output() + status.Output() + "%K{" + color + "}%F{black} %f%k%F{" + color + "} %f")
+		config.Format(cfg.Line1, outputs) + "\n" +
+		config.Format(cfg.Line2, outputs) + "%K{" + color + "}%F{black} %f%k%F{" + color + "} %f")
 }
 
 type module interface {
-	Init(ctx *Context) error
+	Init(ctx context.Context, pctx *Context, cfg *config.Config) error
 	Output() string
 }
 
@@ -122,32 +224,195 @@ type KubernetesModule struct {
 	output string
 }
 type kubeconfig struct {
-	CurrentContext string `json:"current-context"`
+	CurrentContext string          `json:"current-context"`
+	Contexts       []kubeconfigCtx `json:"contexts"`
+}
+type kubeconfigCtx struct {
+	Name    string `json:"name"`
+	Context struct {
+		Cluster   string `json:"cluster"`
+		User      string `json:"user"`
+		Namespace string `json:"namespace"`
+	} `json:"context"`
 }
 
-func (m *KubernetesModule) Init(ctx *Context) error {
-	kubeconfigFile, err := ioutil.ReadFile(
-		path.Join(ctx.Home, ".kube", "config"))
-	if os.IsNotExist(err) {
-		return nil
-	}
+func (m *KubernetesModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
+	kc, err := loadKubeconfig(pctx)
 	if err != nil {
-		return fmt.Errorf("reading kubeconfig: %w", err)
+		return err
 	}
+	if kc == nil || kc.CurrentContext == "" {
+		return nil
+	}
+
+	namespace := "default"
+	var cluster, user string
+	for _, c := range kc.Contexts {
+		if c.Name != kc.CurrentContext {
+			continue
+		}
+		if c.Context.Namespace != "" {
+			namespace = c.Context.Namespace
+		}
+		cluster = c.Context.Cluster
+		user = c.Context.User
+		break
+	}
+
+	m.output = "%B%F{blue} ☸ " + kc.CurrentContext + "/" + namespace
+
+	switch os.Getenv("SPACE_PROMPT_KUBE_DETAIL") {
+	case "context+ns":
+		// already included above
+	case "full":
+		if user != "" {
+			m.output += "(" + user + "@" + cluster + ")"
+		} else if cluster != "" {
+			m.output += "(" + cluster + ")"
+		}
+	case "context":
+		m.output = "%B%F{blue} ☸ " + kc.CurrentContext
+	}
+
+	m.output += "%b%f"
+	return nil
+}
+
+// loadKubeconfig reads and merges the kubeconfig files referenced by the
+// KUBECONFIG env var (colon-separated), falling back to ~/.kube/config when
+// KUBECONFIG is unset. It mirrors kubectl's precedence rules: the first file
+// to set current-context wins, while later files' context definitions win
+// over earlier ones with the same name.
+func loadKubeconfig(pctx *Context) (*kubeconfig, error) {
+	files := filepath.SplitList(os.Getenv("KUBECONFIG"))
+	if len(files) == 0 {
+		files = []string{path.Join(pctx.Home, ".kube", "config")}
+	}
+
+	merged := &kubeconfig{}
+	contexts := map[string]kubeconfigCtx{}
+	var found bool
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading kubeconfig: %w", err)
+		}
+		found = true
+
+		kc := &kubeconfig{}
+		if err := yaml.Unmarshal(b, kc); err != nil {
+			return nil, fmt.Errorf("unmarshal yaml: %w", err)
+		}
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = kc.CurrentContext
+		}
+		for _, c := range kc.Contexts {
+			contexts[c.Name] = c
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	for _, c := range contexts {
+		merged.Contexts = append(merged.Contexts, c)
+	}
+	return merged, nil
+}
+
+func (m *KubernetesModule) Output() string {
+	return m.output
+}
 
-	kc := &kubeconfig{}
-	if err := yaml.Unmarshal(kubeconfigFile, kc); err != nil {
-		return fmt.Errorf("unmarshal yaml: %w", err)
+// ---------
+// Container
+// ---------
+
+type ContainerModule struct {
+	output string
+}
+
+type dockerConfig struct {
+	CurrentContext string `json:"currentContext"`
+}
+
+type containersConf struct {
+	Engine struct {
+		ActiveService       string              `toml:"active_service"`
+		ServiceDestinations map[string]struct{} `toml:"service_destinations"`
+	} `toml:"engine"`
+}
+
+func (m *ContainerModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
+	docker, err := dockerContext(pctx)
+	if err != nil {
+		return err
 	}
-	if kc.CurrentContext == "" {
+	if docker != "" {
+		m.output = `%F{cyan} docker:` + docker + `%f`
 		return nil
 	}
 
-	m.output = "%B%F{blue} ☸ " + kc.CurrentContext + "%b%f"
+	podman, err := podmanContext(pctx)
+	if err != nil {
+		return err
+	}
+	if podman != "" {
+		m.output = `%F{cyan} podman:` + podman + `%f`
+	}
 	return nil
 }
 
-func (m *KubernetesModule) Output() string {
+func dockerContext(pctx *Context) (string, error) {
+	b, err := ioutil.ReadFile(path.Join(pctx.Home, ".docker", "config.json"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading docker config: %w", err)
+	}
+
+	dc := &dockerConfig{}
+	if err := json.Unmarshal(b, dc); err != nil {
+		return "", fmt.Errorf("unmarshal json: %w", err)
+	}
+	return dc.CurrentContext, nil
+}
+
+// podmanContext reads $CONTAINERS_CONF, falling back to
+// ~/.config/containers/containers.conf, and returns the name of the active
+// service when it is present in [engine.service_destinations].
+func podmanContext(pctx *Context) (string, error) {
+	file := os.Getenv("CONTAINERS_CONF")
+	if file == "" {
+		file = path.Join(pctx.Home, ".config", "containers", "containers.conf")
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading containers.conf: %w", err)
+	}
+
+	cc := &containersConf{}
+	if _, err := toml.Decode(string(b), cc); err != nil {
+		return "", fmt.Errorf("unmarshal toml: %w", err)
+	}
+	if cc.Engine.ActiveService == "" {
+		return "", nil
+	}
+	if _, ok := cc.Engine.ServiceDestinations[cc.Engine.ActiveService]; !ok {
+		return "", nil
+	}
+	return cc.Engine.ActiveService, nil
+}
+
+func (m *ContainerModule) Output() string {
 	return m.output
 }
 
@@ -158,23 +423,39 @@ type DirectoryModule struct {
 	output string
 }
 
-func (m *DirectoryModule) Init(ctx *Context) error {
+func (m *DirectoryModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
 	wd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
 
 	var dir string
-	if wd == ctx.Home {
+	if wd == pctx.Home {
 		dir = "~"
+	} else if n := cfg.Modules.Directory.TruncateLength; n > 0 {
+		dir = truncatePath(wd, n)
 	} else {
 		dir = path.Base(wd)
 	}
 
-	m.output = `%F{white} in%f %F{cyan}%B` + dir + `%b%f`
+	color := cfg.Modules.Directory.Color
+	if color == "" {
+		color = "cyan"
+	}
+
+	m.output = `%F{white} in%f %F{` + color + `}%B` + dir + `%b%f`
 	return nil
 }
 
+// truncatePath keeps only the last n path segments of wd, joined by "/".
+func truncatePath(wd string, n int) string {
+	segments := strings.Split(strings.Trim(wd, "/"), "/")
+	if len(segments) > n {
+		segments = segments[len(segments)-n:]
+	}
+	return strings.Join(segments, "/")
+}
+
 func (m *DirectoryModule) Output() string {
 	return m.output
 }
@@ -186,7 +467,7 @@ type HostnameModule struct {
 	output string
 }
 
-func (m *HostnameModule) Init(ctx *Context) error {
+func (m *HostnameModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
 	hostname, err := os.Hostname()
 	if err != nil {
 		return fmt.Errorf("getting hostname: %w", err)
@@ -197,7 +478,7 @@ func (m *HostnameModule) Init(ctx *Context) error {
 		hostname = hostname[:idx]
 	}
 
-	if ctx.InSSH {
+	if pctx.InSSH {
 		m.output += "%K{black} ﴽ%k"
 	}
 	m.output += "%K{black}%F{white} " + hostname + "%k%f"
@@ -215,7 +496,7 @@ type GolangModule struct {
 	output string
 }
 
-func (m *GolangModule) Init(ctx *Context) error {
+func (m *GolangModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
 	_, err := os.Stat("go.mod")
 	if os.IsNotExist(err) {
 		return nil
@@ -224,7 +505,7 @@ func (m *GolangModule) Init(ctx *Context) error {
 		return fmt.Errorf("checking go.mod: %w", err)
 	}
 
-	version, err := exec.Command("go", "version").Output()
+	version, err := exec.CommandContext(ctx, "go", "version").Output()
 	if err != nil {
 		return nil
 	}
@@ -252,8 +533,8 @@ type StatusModule struct {
 	output string
 }
 
-func (m *StatusModule) Init(ctx *Context) error {
-	if ctx.Status == 0 {
+func (m *StatusModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
+	if pctx.Status == 0 {
 		m.output = "%B%K{black}%F{green} ✓ %k%f%b"
 		return nil
 	}
@@ -272,12 +553,27 @@ type TookModule struct {
 	output string
 }
 
-func (m *TookModule) Init(ctx *Context) error {
-	if ctx.Duration < time.Second*2 {
+func (m *TookModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
+	minDuration := 2 * time.Second
+	if cfg.Modules.Took.MinDuration != "" {
+		if d, err := time.ParseDuration(cfg.Modules.Took.MinDuration); err == nil {
+			minDuration = d
+		}
+	}
+	if pctx.Duration < minDuration {
 		return nil
 	}
 
-	m.output = ` took %B%F{yellow}` + ctx.Duration.Round(time.Millisecond).String() + `%b%f`
+	color := cfg.Modules.Took.Color
+	if color == "" {
+		color = "yellow"
+	}
+	symbol := cfg.Modules.Took.Symbol
+	if symbol == "" {
+		symbol = "took"
+	}
+
+	m.output = ` ` + symbol + ` %B%F{` + color + `}` + pctx.Duration.Round(time.Millisecond).String() + `%b%f`
 	return nil
 }
 
@@ -292,7 +588,7 @@ type UserModule struct {
 	output string
 }
 
-func (m *UserModule) Init(ctx *Context) error {
+func (m *UserModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
 	u, err := user.Current()
 	if err != nil {
 		return fmt.Errorf("get current user: %w", err)
@@ -328,30 +624,60 @@ var (
 )
 
 const (
-	GitUntracked = "?"
-	GitAdded     = "+"
-	GitModified  = "!"
-	GitRenamed   = "»"
-	GitDeleted   = "✘"
-	GitStashed   = "$"
-	GitUnmerged  = "="
-	GitAhead     = "⇡"
-	GitBehind    = "⇣"
-	GitDiverged  = "⇕"
+	GitUntracked    = "?"
+	GitAdded        = "+"
+	GitModified     = "!"
+	GitRenamed      = "»"
+	GitDeleted      = "✘"
+	GitStashed      = "$"
+	GitUnmerged     = "="
+	GitAhead        = "⇡"
+	GitBehind       = "⇣"
+	GitDiverged     = "⇕"
+	GitPullRequests = "⇅"
 )
 
+// DefaultGitPRCacheTTL is how long a cached open-PR count is trusted before
+// GitModule queries the hosting provider's API again.
+const DefaultGitPRCacheTTL = 60 * time.Second
+
+func gitPRCacheTTL() time.Duration {
+	if v := os.Getenv("SPACE_PROMPT_GIT_PR_CACHE_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultGitPRCacheTTL
+}
+
+// DefaultGitPRRefreshTimeout bounds the background API call that refreshes a
+// stale or missing open-PR count. It runs in a goroutine detached from the
+// module's own deadline. The goroutine ends when the process exits after
+// printing the prompt, so the cache is only written when the call completes
+// before then.
+const DefaultGitPRRefreshTimeout = 5 * time.Second
+
+func gitPRRefreshTimeout() time.Duration {
+	if v := os.Getenv("SPACE_PROMPT_GIT_PR_REFRESH_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return DefaultGitPRRefreshTimeout
+}
+
 type GitModule struct {
 	output string
 }
 
-func (m *GitModule) Init(ctx *Context) error {
-	output, err := exec.Command("git", "status", "--porcelain", "-b").Output()
+func (m *GitModule) Init(ctx context.Context, pctx *Context, cfg *config.Config) error {
+	output, err := exec.CommandContext(ctx, "git", "status", "--porcelain", "-b").Output()
 	if err != nil {
 		// no git?
 		return nil
 	}
 
-	stash := exec.Command("git", "rev-parse", "--verify", "refs/stash").Run() == nil
+	stash := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "refs/stash").Run() == nil
 
 	// branch
 	if len(output) < 4 {
@@ -361,39 +687,49 @@ func (m *GitModule) Init(ctx *Context) error {
 	branch := string(output[3:branchEndIndex])
 	branch = strings.TrimPrefix(branch, "No commits yet on ")
 
+	disabled := map[string]bool{}
+	for _, f := range cfg.Modules.Git.DisabledStatusFlags {
+		disabled[f] = true
+	}
 	var status string
+	addFlag := func(flag string) {
+		if !disabled[flag] {
+			status += flag
+		}
+	}
+
 	// untracked files
 	if bytes.Contains(output, []byte("\n??")) {
-		status += GitUntracked
+		addFlag(GitUntracked)
 	}
 
 	// staged
 	if stagedRegEx.Match(output) {
-		status += GitAdded
+		addFlag(GitAdded)
 	}
 
 	// modified
 	if modifiedRegEx.Match(output) {
-		status += GitModified
+		addFlag(GitModified)
 	}
 
 	// renamed
 	if renamedRegEx.Match(output) {
-		status += GitRenamed
+		addFlag(GitRenamed)
 	}
 
 	// deleted
 	if deletedRegEx.Match(output) {
-		status += GitDeleted
+		addFlag(GitDeleted)
 	}
 
 	if stash {
-		status += GitStashed
+		addFlag(GitStashed)
 	}
 
 	// unmerged
 	if unmergedRegEx.Match(output) {
-		status += GitUnmerged
+		addFlag(GitUnmerged)
 	}
 
 	var (
@@ -401,20 +737,125 @@ func (m *GitModule) Init(ctx *Context) error {
 		isBehind = behindRegEx.Match(output)
 	)
 	if isAhead && isBehind {
-		status += GitDiverged
+		addFlag(GitDiverged)
 	} else if isAhead {
-		status += GitAhead
+		addFlag(GitAhead)
 	} else if isBehind {
-		status += GitBehind
+		addFlag(GitBehind)
+	}
+
+	var icon string
+	if remoteURL, err := exec.CommandContext(ctx, "git", "remote", "get-url", "origin").Output(); err == nil {
+		if prv, host, owner, repo, ok := gitprovider.Detect(strings.TrimSpace(string(remoteURL))); ok {
+			icon = prv.Icon()
+			if n, ok := gitPullRequestCount(pctx, prv, host, owner, repo, branch); ok && n > 0 && !disabled[GitPullRequests] {
+				status += GitPullRequests + strconv.Itoa(n)
+			}
+		}
+	}
+
+	color := cfg.Modules.Git.Color
+	if color == "" {
+		color = "magenta"
 	}
 
-	m.output = `%F{white} on%f%F{magenta}%B  ` + branch + `%b%f`
+	if icon == "" {
+		icon = ""
+	}
+	m.output = `%F{white} on%f%F{` + color + `}%B ` + icon + ` ` + branch + `%b%f`
 	if status != "" {
 		m.output += ` %F{red}[` + status + `]%f`
 	}
 	return nil
 }
 
+// gitPullRequestCount returns the number of open pull requests with head
+// branch equal to branch, read from the on-disk cache. GitModule never
+// blocks on network I/O: when the cache is missing or older than
+// gitPRCacheTTL, a refresh is kicked off in the background for next time and
+// this call still returns whatever (possibly stale) value was on disk.
+func gitPullRequestCount(pctx *Context, prv gitprovider.Provider, host, owner, repo, branch string) (int, bool) {
+	cachePath := gitPRCachePath(pctx, host, owner, repo, branch)
+	var cached *gitPRCache
+	if b, err := ioutil.ReadFile(cachePath); err == nil {
+		var c gitPRCache
+		if json.Unmarshal(b, &c) == nil {
+			cached = &c
+		}
+	}
+
+	if cached == nil || time.Since(cached.FetchedAt) >= gitPRCacheTTL() {
+		refreshGitPullRequestCount(pctx, prv, host, owner, repo, branch, cachePath)
+	}
+
+	if cached == nil {
+		return 0, false
+	}
+	return cached.Count, true
+}
+
+// refreshGitPullRequestCount queries the provider's API in the background and
+// writes the result to cachePath for the next invocation. It requires a
+// token for host in ~/.config/space-prompt/git-tokens.yaml; without one it's
+// a no-op, since an unauthenticated call isn't worth the background work.
+func refreshGitPullRequestCount(pctx *Context, prv gitprovider.Provider, host, owner, repo, branch, cachePath string) {
+	tokens, err := loadGitTokens(pctx)
+	if err != nil || tokens[host] == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), gitPRRefreshTimeout())
+		defer cancel()
+
+		count, err := prv.OpenPullRequestCount(ctx, http.DefaultClient, tokens[host], owner, repo, branch)
+		if err != nil {
+			return
+		}
+
+		b, err := json.Marshal(gitPRCache{Count: count, FetchedAt: time.Now()})
+		if err != nil {
+			return
+		}
+		if err := os.MkdirAll(path.Dir(cachePath), 0o755); err != nil {
+			return
+		}
+		_ = ioutil.WriteFile(cachePath, b, 0o644)
+	}()
+}
+
+type gitPRCache struct {
+	Count     int       `json:"count"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+func gitPRCachePath(pctx *Context, host, owner, repo, branch string) string {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = path.Join(pctx.Home, ".cache")
+	}
+	sum := sha256.Sum256([]byte(host + "/" + owner + "/" + repo + "/" + branch))
+	return path.Join(cacheHome, "space-prompt", "git", hex.EncodeToString(sum[:])+".json")
+}
+
+// loadGitTokens reads the optional host->token map used to authenticate
+// pull-request lookups against private git hosting providers.
+func loadGitTokens(pctx *Context) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path.Join(pctx.Home, ".config", "space-prompt", "git-tokens.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading git-tokens.yaml: %w", err)
+	}
+
+	tokens := map[string]string{}
+	if err := yaml.Unmarshal(b, &tokens); err != nil {
+		return nil, fmt.Errorf("unmarshal yaml: %w", err)
+	}
+	return tokens, nil
+}
+
 func (m *GitModule) Output() string {
 	return m.output
 }
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Go-Spaceship Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the optional space-prompt configuration file, which
+// lets users pick which modules are enabled, in what order they render on
+// each line, and per-module options like color, symbol or threshold.
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the root of the space-prompt configuration file.
+type Config struct {
+	// Line1 lists the module names rendered on the first prompt line, in order.
+	Line1 []string `json:"line1,omitempty" toml:"line1,omitempty"`
+	// Line2 lists the module names rendered on the second prompt line, in order.
+	Line2 []string `json:"line2,omitempty" toml:"line2,omitempty"`
+
+	Modules ModulesConfig `json:"modules,omitempty" toml:"modules,omitempty"`
+}
+
+// ModulesConfig holds per-module options, keyed by module name.
+type ModulesConfig struct {
+	Took      TookConfig      `json:"took,omitempty" toml:"took,omitempty"`
+	Directory DirectoryConfig `json:"directory,omitempty" toml:"directory,omitempty"`
+	Git       GitConfig       `json:"git,omitempty" toml:"git,omitempty"`
+}
+
+// TookConfig configures the "took" module.
+type TookConfig struct {
+	// MinDuration is the minimum command duration, parseable by
+	// time.ParseDuration, before the module renders anything. Defaults to
+	// "2s" when empty or unparseable.
+	MinDuration string `json:"min_duration,omitempty" toml:"min_duration,omitempty"`
+	// Color overrides the module's zsh prompt color. Defaults to "yellow".
+	Color string `json:"color,omitempty" toml:"color,omitempty"`
+	// Symbol is printed before the duration. Defaults to "took".
+	Symbol string `json:"symbol,omitempty" toml:"symbol,omitempty"`
+}
+
+// DirectoryConfig configures the "directory" module.
+type DirectoryConfig struct {
+	// TruncateLength is the maximum number of trailing path segments to
+	// show. 0 (the default) shows only the last segment.
+	TruncateLength int `json:"truncate_length,omitempty" toml:"truncate_length,omitempty"`
+	// Color overrides the module's zsh prompt color. Defaults to "cyan".
+	Color string `json:"color,omitempty" toml:"color,omitempty"`
+}
+
+// GitConfig configures the "git" module.
+type GitConfig struct {
+	// DisabledStatusFlags lists status flags (e.g. "?", "!") to omit from
+	// the rendered status block.
+	DisabledStatusFlags []string `json:"disabled_status_flags,omitempty" toml:"disabled_status_flags,omitempty"`
+	// Color overrides the module's zsh prompt color. Defaults to "magenta".
+	Color string `json:"color,omitempty" toml:"color,omitempty"`
+}
+
+// Default returns the configuration matching space-prompt's built-in,
+// hard-coded layout.
+func Default() *Config {
+	return &Config{
+		Line1: []string{"user", "kubernetes", "container", "directory", "git", "golang", "took"},
+		Line2: []string{"hostname", "status"},
+	}
+}
+
+// Load searches $XDG_CONFIG_HOME/space-prompt/ and then
+// ~/.config/space-prompt/ for a config.yaml or config.toml file and returns
+// the first one found. It returns the default configuration when neither
+// exists.
+func Load(home string) (*Config, error) {
+	for _, dir := range searchDirs(home) {
+		for _, name := range []string{"config.yaml", "config.toml"} {
+			file := filepath.Join(dir, "space-prompt", name)
+			b, err := ioutil.ReadFile(file)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			cfg := Default()
+			if err := unmarshal(name, b, cfg); err != nil {
+				return nil, err
+			}
+			return cfg, nil
+		}
+	}
+	return Default(), nil
+}
+
+func unmarshal(file string, b []byte, cfg *Config) error {
+	if strings.HasSuffix(file, ".toml") {
+		return toml.Unmarshal(b, cfg)
+	}
+	return yaml.Unmarshal(b, cfg)
+}
+
+func searchDirs(home string) []string {
+	var dirs []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dirs = append(dirs, xdg)
+	}
+	dirs = append(dirs, filepath.Join(home, ".config"))
+	return dirs
+}
+
+// Format renders a prompt line by looking up each name in order and joining
+// the corresponding output, skipping modules that produced no output.
+func Format(names []string, outputs map[string]string) string {
+	var line string
+	for _, name := range names {
+		line += outputs[name]
+	}
+	return line
+}
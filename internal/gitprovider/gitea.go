@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Go-Spaceship Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type giteaProvider struct {
+	host string
+}
+
+func (p *giteaProvider) Name() string { return "Gitea" }
+func (p *giteaProvider) Icon() string { return "" }
+
+func (p *giteaProvider) OpenPullRequestCount(ctx context.Context, client *http.Client, token, owner, repo, branch string) (int, error) {
+	url := fmt.Sprintf(
+		"https://%s/api/v1/repos/%s/%s/pulls?state=open",
+		p.host, owner, repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("gitea api: unexpected status %s", res.Status)
+	}
+
+	var pulls []struct {
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&pulls); err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, pr := range pulls {
+		if pr.Head.Ref == branch {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Go-Spaceship Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type githubProvider struct{}
+
+func (p *githubProvider) Name() string { return "GitHub" }
+func (p *githubProvider) Icon() string { return "" }
+
+func (p *githubProvider) OpenPullRequestCount(ctx context.Context, client *http.Client, token, owner, repo, branch string) (int, error) {
+	url := fmt.Sprintf(
+		"https://api.github.com/repos/%s/%s/pulls?state=open&head=%s:%s",
+		owner, repo, owner, branch)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("github api: unexpected status %s", res.Status)
+	}
+
+	var pulls []struct{}
+	if err := json.NewDecoder(res.Body).Decode(&pulls); err != nil {
+		return 0, err
+	}
+	return len(pulls), nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Go-Spaceship Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gitprovider detects the hosting provider behind a git remote and,
+// for providers that implement it, looks up the number of open pull requests
+// for a branch.
+package gitprovider
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// Provider is implemented by every supported git hosting provider.
+type Provider interface {
+	// Name is the provider's human readable name, e.g. "GitHub".
+	Name() string
+	// Icon is the glyph rendered before the branch name in GitModule. When it
+	// is empty, GitModule renders its default branch glyph. Every provider in
+	// this package returns an empty icon, so all remotes render with that
+	// default glyph.
+	Icon() string
+	// OpenPullRequestCount returns the number of open pull/merge requests
+	// with head branch equal to branch.
+	OpenPullRequestCount(ctx context.Context, client *http.Client, token, owner, repo, branch string) (int, error)
+}
+
+var remoteURLRegEx = regexp.MustCompile(`^(?:https?://|git@|ssh://git@)([^/:]+)[/:]([^/]+)/(.+?)(?:\.git)?$`)
+
+// Detect parses a `git remote get-url` style URL and returns the matching
+// Provider along with the host, owner and repo it was built for. ok is false
+// when the URL could not be parsed.
+func Detect(remoteURL string) (p Provider, host, owner, repo string, ok bool) {
+	m := remoteURLRegEx.FindStringSubmatch(remoteURL)
+	if m == nil {
+		return nil, "", "", "", false
+	}
+	host, owner, repo = m[1], m[2], m[3]
+
+	switch {
+	case host == "github.com" || hasPrefix(host, "github."):
+		// github.<company>.com is GitHub Enterprise, which speaks the same API.
+		return &githubProvider{}, host, owner, repo, true
+	case host == "gitlab.com" || hasPrefix(host, "gitlab."):
+		return &iconOnlyProvider{name: "GitLab", icon: ""}, host, owner, repo, true
+	case host == "bitbucket.org" || hasPrefix(host, "bitbucket."):
+		return &iconOnlyProvider{name: "Bitbucket", icon: ""}, host, owner, repo, true
+	case hasPrefix(host, "gitea.") || hasPrefix(host, "forgejo."):
+		return &giteaProvider{host: host}, host, owner, repo, true
+	default:
+		// An unrecognized self-hosted remote: we don't know which API it
+		// speaks, so render it with no icon and skip pull request lookups
+		// rather than guessing at a provider.
+		return &iconOnlyProvider{name: host, icon: ""}, host, owner, repo, true
+	}
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+// iconOnlyProvider is used for providers we can recognize but don't yet query
+// for pull request counts.
+type iconOnlyProvider struct {
+	name, icon string
+}
+
+func (p *iconOnlyProvider) Name() string { return p.name }
+func (p *iconOnlyProvider) Icon() string { return p.icon }
+func (p *iconOnlyProvider) OpenPullRequestCount(ctx context.Context, client *http.Client, token, owner, repo, branch string) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 The Go-Spaceship Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin lets users drop custom module binaries into
+// ~/.config/space-prompt/plugins and have them rendered alongside the
+// built-in modules. Plugins are invoked over gRPC via hashicorp/go-plugin,
+// mirroring the built-in `module` interface (Init/Output).
+package plugin
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/thetechnick/go-space-prompt/internal/plugin/proto"
+)
+
+// DefaultTimeout is the per-plugin deadline applied when the user has not
+// overridden it via SPACE_PROMPT_PLUGIN_TIMEOUT.
+const DefaultTimeout = 150 * time.Millisecond
+
+// Handshake is shared between host and plugin so both sides agree on the
+// protocol version before anything is dispensed.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "SPACE_PROMPT_PLUGIN",
+	MagicCookieValue: "module",
+}
+
+// modulePlugin implements go-plugin's plugin.GRPCPlugin, making a
+// proto.ModuleServer dispensable as a proto.ModuleClient over gRPC.
+type modulePlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	Impl proto.ModuleServer
+}
+
+func (p *modulePlugin) GRPCServer(broker *goplugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterModuleServer(s, p.Impl)
+	return nil
+}
+
+func (p *modulePlugin) GRPCClient(ctx context.Context, broker *goplugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return proto.NewModuleClient(c), nil
+}
+
+// PluginMap is the map go-plugin expects. Every space-prompt plugin binary
+// dispenses exactly one "module" plugin.
+var PluginMap = map[string]goplugin.Plugin{
+	"module": &modulePlugin{},
+}
+
+// Client wraps a single discovered plugin binary and its rendered output.
+type Client struct {
+	Name string
+
+	path    string
+	timeout time.Duration
+
+	client *goplugin.Client
+	output string
+}
+
+// Discover returns one Client per executable file found directly inside dir.
+// A missing directory is not an error: most users have no plugins installed.
+func Discover(dir string, timeout time.Duration) []*Client {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var clients []*Client
+	for _, e := range entries {
+		if e.IsDir() || e.Mode()&0111 == 0 {
+			continue
+		}
+		clients = append(clients, &Client{
+			Name:    e.Name(),
+			path:    filepath.Join(dir, e.Name()),
+			timeout: timeout,
+		})
+	}
+	return clients
+}
+
+// Init launches the plugin subprocess, calls its Init and Output rpcs and
+// stores the result. Any failure -- the process not starting, a handshake
+// mismatch, or the rpc missing its deadline -- is swallowed, leaving the
+// plugin with no output so a broken plugin never blocks or corrupts the
+// prompt. Launch, handshake and both rpcs are all bounded by c.timeout, so a
+// plugin binary that hangs on startup can't stall the prompt either.
+func (c *Client) Init(ctx context.Context, in *proto.InitRequest) {
+	c.client = goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          PluginMap,
+		Cmd:              exec.Command(c.path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		StartTimeout:     c.timeout,
+	})
+
+	rpcClient, err := c.client.Client()
+	if err != nil {
+		return
+	}
+	raw, err := rpcClient.Dispense("module")
+	if err != nil {
+		return
+	}
+	mc, ok := raw.(proto.ModuleClient)
+	if !ok {
+		return
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if _, err := mc.Init(rpcCtx, in); err != nil {
+		return
+	}
+
+	out, err := mc.Output(rpcCtx, &proto.OutputRequest{})
+	if err != nil {
+		return
+	}
+	c.output = out.Output
+}
+
+// Output returns the plugin's rendered output, or "" if it failed or missed
+// its deadline.
+func (c *Client) Output() string {
+	return c.output
+}
+
+// Kill terminates the plugin subprocess. Callers must invoke it once the
+// plugin's Output has been read.
+func (c *Client) Kill() {
+	if c.client != nil {
+		c.client.Kill()
+	}
+}